@@ -0,0 +1,20 @@
+// Package chiadapter adapts a goupload.Core to Chi's router. Chi handlers
+// are plain http.HandlerFunc, so this package is a thin re-export of
+// httpadapter.Handler — it exists so Chi-based code can import
+// "chiadapter" and get a handler under a name that matches the router it's
+// using, without needing to know httpadapter already covers it.
+package chiadapter
+
+import (
+	"net/http"
+
+	"github.com/Palguna1121/goupload"
+	"github.com/Palguna1121/goupload/httpadapter"
+)
+
+// Handler returns an http.HandlerFunc usable directly with Chi
+// (r.Post(pattern, chiadapter.Handler(core))). See httpadapter.Handler for
+// the request formats it accepts.
+func Handler(core *goupload.Core) http.HandlerFunc {
+	return httpadapter.Handler(core)
+}
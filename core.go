@@ -0,0 +1,426 @@
+package goupload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UploadConfig configures a Core (and, through it, ImageUploader and the
+// framework adapter packages).
+type UploadConfig struct {
+	MaxFileSize       int64         // in bytes
+	AllowedExtensions []string      // allowed file extensions
+	StoragePath       string        // base storage path (e.g., "storage/public/images"), used when Storage is nil
+	BaseURL           string        // base URL for serving files (e.g., "http://localhost:8080"), used when Storage is nil
+	EnableTimestamp   bool          // add timestamp to filename
+	CreateDateDir     bool          // create date-based directory structure
+	Storage           Storage       // where files are persisted; defaults to FilesystemStorage using StoragePath/BaseURL
+	Deduplicate       bool          // store by content hash instead of generated filename, skipping duplicate writes
+	Variants          []VariantSpec // derived images (thumbnails, web-sized copies) generated alongside the original
+	Quota             QuotaStore    // when set, Process reserves/releases per-identity byte quota around each file
+	RateLimit         int           // uploads allowed per minute per identity; 0 disables rate limiting
+}
+
+// UploadResult represents the result of an upload operation
+type UploadResult struct {
+	Success       bool                         `json:"success"`
+	Message       string                       `json:"message"`
+	FilePaths     []string                     `json:"file_paths,omitempty"` // relative paths
+	FileURLs      []string                     `json:"file_urls,omitempty"`  // full URLs
+	Hashes        []string                     `json:"hashes,omitempty"`     // SHA-256 hex digests, set when Deduplicate is enabled
+	Variants      map[string]map[string]string `json:"variants,omitempty"`   // filename -> variant name -> URL
+	Error         string                       `json:"error,omitempty"`
+	QuotaExceeded bool                         `json:"quota_exceeded,omitempty"` // set when Quota.Reserve refused the upload
+	RateLimited   bool                         `json:"rate_limited,omitempty"`   // set when RateLimit refused the upload
+}
+
+// ProcessOptions carries the per-request overrides Process needs but that
+// don't belong in UploadConfig, since they vary per call rather than per
+// ImageUploader/Core instance.
+type ProcessOptions struct {
+	SubDir   string // optional subdirectory under the configured storage path
+	MaxSize  int64  // overrides UploadConfig.MaxFileSize for this call when > 0
+	Identity string // caller identity for Quota/RateLimit accounting; empty shares one bucket
+}
+
+// Core implements the upload pipeline (validate, dedupe, store, generate
+// variants) independently of any HTTP framework, so non-Gin adopters and
+// tests can drive it directly. ImageUploader and the framework adapter
+// packages (ginadapter, httpadapter, echoadapter, fiberadapter) are thin
+// wrappers around a Core.
+type Core struct {
+	config      UploadConfig
+	rateLimiter *RateLimiter
+}
+
+// NewCore applies UploadConfig defaults and returns a ready-to-use Core.
+func NewCore(config UploadConfig) *Core {
+	if config.MaxFileSize == 0 {
+		config.MaxFileSize = 10 << 20 // 10MB
+	}
+	if len(config.AllowedExtensions) == 0 {
+		config.AllowedExtensions = []string{"jpg", "jpeg", "png", "webp", "gif", "bmp", "svg"}
+	}
+	if config.StoragePath == "" {
+		config.StoragePath = "storage/app/public/uploads/images"
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = "http://localhost:5220"
+	}
+	if config.Storage == nil {
+		config.Storage = &FilesystemStorage{BasePath: config.StoragePath, BaseURL: config.BaseURL}
+	}
+
+	core := &Core{config: config}
+	if config.RateLimit > 0 {
+		core.rateLimiter = NewRateLimiter(config.RateLimit)
+	}
+	return core
+}
+
+// Config returns the (defaulted) configuration this Core was built with.
+func (c *Core) Config() UploadConfig {
+	return c.config
+}
+
+// Process validates, deduplicates, stores, and generates variants for each
+// of files, the way ProcessUpload always has. It has no knowledge of any
+// HTTP framework: callers are responsible for extracting files and opts
+// from whatever request type they're handling.
+//
+// When Quota is configured, each file's bytes are reserved against
+// opts.Identity before it's written and released if anything about that
+// file fails afterward, unless a variant failure leaves the stored original
+// in place (under Deduplicate, or when deleting it fails). When RateLimit
+// is configured, the whole call is
+// rejected up front once opts.Identity has used its uploads for the
+// current minute.
+func (c *Core) Process(ctx context.Context, files []*multipart.FileHeader, opts ProcessOptions) *UploadResult {
+	if len(files) == 0 {
+		return &UploadResult{
+			Success: false,
+			Message: "No files provided",
+		}
+	}
+
+	if c.rateLimiter != nil && !c.rateLimiter.Allow(opts.Identity) {
+		return &UploadResult{
+			Success:     false,
+			Message:     "Rate limit exceeded",
+			RateLimited: true,
+		}
+	}
+
+	maxSize := c.config.MaxFileSize
+	if opts.MaxSize > 0 {
+		maxSize = opts.MaxSize
+	}
+
+	var filePaths []string
+	var fileURLs []string
+	var hashes []string
+	var variants map[string]map[string]string
+
+	for _, file := range files {
+		processed, failure := c.processFile(ctx, file, opts, maxSize)
+		if failure != nil {
+			return failure
+		}
+
+		filePaths = append(filePaths, processed.relativePath)
+		fileURLs = append(fileURLs, processed.fullURL)
+		if c.config.Deduplicate {
+			hashes = append(hashes, processed.hash)
+		}
+		if len(processed.variants) > 0 {
+			if variants == nil {
+				variants = make(map[string]map[string]string)
+			}
+			variants[file.Filename] = processed.variants
+		}
+	}
+
+	return &UploadResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Successfully uploaded %d file(s)", len(files)),
+		FilePaths: filePaths,
+		FileURLs:  fileURLs,
+		Hashes:    hashes,
+		Variants:  variants,
+	}
+}
+
+// processedFile holds what Process needs out of a single file's pipeline
+// run to append to the aggregate UploadResult.
+type processedFile struct {
+	relativePath string
+	fullURL      string
+	hash         string
+	variants     map[string]string
+}
+
+// processFile runs one file through quota reservation, validation, storage,
+// and variant generation. On failure it returns the UploadResult Process
+// should return immediately, releasing any quota it reserved except when a
+// variant failure leaves the stored original in storage.
+func (c *Core) processFile(ctx context.Context, file *multipart.FileHeader, opts ProcessOptions, maxSize int64) (*processedFile, *UploadResult) {
+	if c.config.Quota != nil {
+		if err := c.config.Quota.Reserve(ctx, opts.Identity, file.Size); err != nil {
+			if errors.Is(err, ErrQuotaExceeded) {
+				return nil, &UploadResult{
+					Success:       false,
+					Message:       fmt.Sprintf("Quota exceeded uploading %s", file.Filename),
+					QuotaExceeded: true,
+				}
+			}
+			return nil, &UploadResult{
+				Success: false,
+				Message: fmt.Sprintf("Failed to reserve quota for %s", file.Filename),
+				Error:   err.Error(),
+			}
+		}
+	}
+
+	release := func() {
+		if c.config.Quota != nil {
+			c.config.Quota.Release(ctx, opts.Identity, file.Size)
+		}
+	}
+
+	if err := c.validateFile(file, maxSize); err != nil {
+		release()
+		return nil, &UploadResult{
+			Success: false,
+			Message: err.Error(),
+		}
+	}
+
+	var relativePath, hash string
+
+	if c.config.Deduplicate {
+		h, err := hashFile(file)
+		if err != nil {
+			release()
+			return nil, &UploadResult{
+				Success: false,
+				Message: fmt.Sprintf("Failed to hash file: %s", file.Filename),
+				Error:   err.Error(),
+			}
+		}
+		hash = h
+		relativePath = dedupKey(hash, filepath.Ext(file.Filename))
+	} else {
+		filename := c.generateFilename(file.Filename)
+		relativePath = c.buildRelativePath(opts.SubDir, filename)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		release()
+		return nil, &UploadResult{
+			Success: false,
+			Message: fmt.Sprintf("Failed to open file: %s", file.Filename),
+			Error:   err.Error(),
+		}
+	}
+
+	fullURL, err := c.config.Storage.Put(ctx, relativePath, src, file.Header.Get("Content-Type"), c.config.Deduplicate)
+	src.Close()
+	if err != nil {
+		release()
+		return nil, &UploadResult{
+			Success: false,
+			Message: fmt.Sprintf("Failed to save file: %s", file.Filename),
+			Error:   err.Error(),
+		}
+	}
+
+	result := &processedFile{relativePath: relativePath, fullURL: fullURL, hash: hash}
+
+	if len(c.config.Variants) > 0 {
+		open := func() (io.ReadCloser, error) { return file.Open() }
+		fileVariants, err := generateVariants(ctx, c.config.Storage, file.Filename, open, relativePath, c.config.Variants)
+		if err != nil {
+			if !c.config.Deduplicate {
+				// relativePath is unique to this upload (not a shared dedup
+				// key), so remove the stored original rather than leave it
+				// orphaned in storage while still releasing its quota
+				// reservation.
+				if delErr := c.config.Storage.Delete(ctx, relativePath); delErr != nil {
+					return nil, &UploadResult{
+						Success: false,
+						Message: fmt.Sprintf("Failed to generate variants for %s, and failed to clean up the stored original", file.Filename),
+						Error:   fmt.Sprintf("%v (variant error: %v)", delErr, err),
+					}
+				}
+				release()
+			}
+			return nil, &UploadResult{
+				Success: false,
+				Message: fmt.Sprintf("Failed to generate variants for %s", file.Filename),
+				Error:   err.Error(),
+			}
+		}
+		result.variants = fileVariants
+	}
+
+	return result, nil
+}
+
+func (c *Core) buildRelativePath(subDir, filename string) string {
+	var parts []string
+
+	if c.config.CreateDateDir {
+		parts = append(parts, time.Now().Format("2006/01/02"))
+	}
+
+	if subDir = sanitizeSubDir(subDir); subDir != "" {
+		parts = append(parts, subDir)
+	}
+
+	parts = append(parts, filename)
+
+	return filepath.ToSlash(filepath.Join(parts...))
+}
+
+// sanitizeSubDir guards against path traversal in a caller-supplied
+// subdirectory (a "sub_dir" form field, a tus Upload-Metadata "subdir"
+// entry or a raw-body X-Sub-Dir header): if any segment of the cleaned
+// path is ".." the whole subdirectory is dropped, while legitimate nested
+// segments like "2024/avatars" are kept.
+func sanitizeSubDir(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	cleaned := strings.TrimPrefix(filepath.ToSlash(filepath.Clean(dir)), "/")
+	for _, segment := range strings.Split(cleaned, "/") {
+		if segment == ".." || segment == "." {
+			return ""
+		}
+	}
+	return cleaned
+}
+
+func (c *Core) validateFile(file *multipart.FileHeader, maxSize int64) error {
+	// Check file size
+	if file.Size > maxSize {
+		return fmt.Errorf("file %s exceeds maximum size of %s", file.Filename, c.formatBytes(maxSize))
+	}
+
+	// Check file extension
+	if !c.isAllowedExtension(file.Filename) {
+		return fmt.Errorf("file %s has disallowed extension. Allowed: %s",
+			file.Filename, strings.Join(c.config.AllowedExtensions, ", "))
+	}
+
+	// Check MIME type
+	mime, err := c.detectMimeType(file)
+	if err != nil {
+		return fmt.Errorf("failed to read MIME type for %s: %v", file.Filename, err)
+	}
+
+	if !c.isAllowedMimeType(mime) {
+		return fmt.Errorf("file %s has disallowed MIME type: %s", file.Filename, mime)
+	}
+
+	return nil
+}
+
+func (c *Core) isAllowedExtension(filename string) bool {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+	for _, allowed := range c.config.AllowedExtensions {
+		if ext == strings.ToLower(allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Core) generateFilename(original string) string {
+	ext := filepath.Ext(original)
+	name := strings.TrimSuffix(original, ext)
+
+	// Clean filename
+	name = strings.ReplaceAll(name, " ", "_")
+	name = strings.ReplaceAll(name, "-", "_")
+
+	if c.config.EnableTimestamp {
+		timestamp := time.Now().Format("20060102_150405")
+		return fmt.Sprintf("%s_%s%s", name, timestamp, ext)
+	}
+
+	return fmt.Sprintf("%s_%d%s", name, time.Now().Unix(), ext)
+}
+
+// ParseSize parses a human-readable size like "10mb" or "2.5GB" into bytes.
+// It's exported so framework adapters can honor the same "max_size" form
+// field convention ProcessUpload does.
+func (c *Core) ParseSize(input string) (int64, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	multiplier := int64(1)
+
+	if strings.HasSuffix(input, "kb") {
+		multiplier = 1 << 10
+		input = strings.TrimSuffix(input, "kb")
+	} else if strings.HasSuffix(input, "mb") {
+		multiplier = 1 << 20
+		input = strings.TrimSuffix(input, "mb")
+	} else if strings.HasSuffix(input, "gb") {
+		multiplier = 1 << 30
+		input = strings.TrimSuffix(input, "gb")
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(input), 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(value * float64(multiplier)), nil
+}
+
+func (c *Core) formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+func (c *Core) detectMimeType(file *multipart.FileHeader) (string, error) {
+	f, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return SniffMIME(f)
+}
+
+func (c *Core) isAllowedMimeType(mime string) bool {
+	allowedMimes := []string{
+		"image/jpeg",
+		"image/png",
+		"image/webp",
+		"image/gif",
+		"image/bmp",
+		"image/svg+xml",
+	}
+	for _, allowed := range allowedMimes {
+		if mime == allowed {
+			return true
+		}
+	}
+	return false
+}
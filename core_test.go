@@ -0,0 +1,129 @@
+package goupload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeSubDir(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"avatars", "avatars"},
+		{"2024/avatars", "2024/avatars"},
+		{"..", ""},
+		{"../../../../tmp", ""},
+		{"avatars/../../../etc", ""},
+	}
+	for _, c := range cases {
+		if got := sanitizeSubDir(c.in); got != c.want {
+			t.Errorf("sanitizeSubDir(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestBuildRelativePathRejectsTraversal(t *testing.T) {
+	core := NewCore(UploadConfig{})
+
+	got := core.buildRelativePath("../../../../tmp", "evil.jpg")
+	if got != "evil.jpg" {
+		t.Errorf("buildRelativePath with a traversal subDir = %q, want it dropped entirely (\"evil.jpg\")", got)
+	}
+}
+
+// failOnSubdirStorage wraps a Storage and fails Put for any key under a
+// given subdirectory, so tests can simulate variant generation failing
+// after the original file was already stored successfully.
+type failOnSubdirStorage struct {
+	Storage
+	failSubdir string
+}
+
+func (s *failOnSubdirStorage) Put(ctx context.Context, key string, reader io.Reader, contentType string, skipIfExists bool) (string, error) {
+	if strings.HasPrefix(key, s.failSubdir+"/") || strings.Contains(key, "/"+s.failSubdir+"/") {
+		return "", errors.New("simulated variant storage failure")
+	}
+	return s.Storage.Put(ctx, key, reader, contentType, skipIfExists)
+}
+
+func newMultipartFileHeader(t *testing.T, fieldName, filename string, content []byte) *multipart.FileHeader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile(fieldName, filename)
+	if err != nil {
+		t.Fatalf("CreateFormFile: %v", err)
+	}
+	if _, err := part.Write(content); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	if err := req.ParseMultipartForm(32 << 20); err != nil {
+		t.Fatalf("ParseMultipartForm: %v", err)
+	}
+	return req.MultipartForm.File[fieldName][0]
+}
+
+func encodeTestJPEG(t *testing.T) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("jpeg.Encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcessFileCleansUpOrphanedOriginalOnVariantFailure(t *testing.T) {
+	storageDir := t.TempDir()
+	base := &FilesystemStorage{BasePath: storageDir}
+	storage := &failOnSubdirStorage{Storage: base, failSubdir: "thumb"}
+
+	core := NewCore(UploadConfig{
+		AllowedExtensions: []string{"jpg"},
+		Storage:           storage,
+		Variants:          []VariantSpec{{Name: "thumb", Width: 10, Height: 10, Fit: "cover", Format: "jpeg"}},
+	})
+
+	jpegBytes := encodeTestJPEG(t)
+	file := newMultipartFileHeader(t, "file", "photo.jpg", jpegBytes)
+
+	result := core.Process(context.Background(), []*multipart.FileHeader{file}, ProcessOptions{})
+	if result.Success {
+		t.Fatalf("expected Process to fail when variant generation fails, got success")
+	}
+
+	entries, err := os.ReadDir(storageDir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected the stored original to be cleaned up after variant failure, found %d entries under storageDir", len(entries))
+	}
+}
@@ -0,0 +1,57 @@
+package goupload
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+)
+
+// hashFile streams file's content through SHA-256 without loading it into
+// memory, returning the hex digest. The file is reopened afterwards by the
+// caller since this consumes the stream.
+func hashFile(file *multipart.FileHeader) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, src); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashLocalFile is hashFile's counterpart for files that already exist on
+// local disk rather than arriving as a multipart.FileHeader.
+func hashLocalFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// dedupKey builds the content-addressed storage key for a file with the
+// given hex SHA-256 hash, sharded by the first two bytes (four hex chars)
+// so no single directory ends up with millions of entries, e.g.
+// "ab/cd/abcd1234...ext".
+func dedupKey(hash, ext string) string {
+	if len(hash) < 4 {
+		return fmt.Sprintf("%s%s", hash, ext)
+	}
+	return filepath.ToSlash(filepath.Join(hash[0:2], hash[2:4], hash+ext))
+}
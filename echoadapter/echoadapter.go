@@ -0,0 +1,70 @@
+// Package echoadapter adapts a goupload.Core to an echo.HandlerFunc.
+package echoadapter
+
+import (
+	"fmt"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/Palguna1121/goupload"
+	"github.com/labstack/echo/v4"
+)
+
+// Handler returns an echo.HandlerFunc that runs uploads through core,
+// reading "sub_dir"/"max_size" form fields and "files"/"images"/"file"/
+// "image" file fields the same way goupload.ImageUploader does.
+func Handler(core *goupload.Core) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		opts := goupload.ProcessOptions{SubDir: c.FormValue("sub_dir"), Identity: c.RealIP()}
+		if maxSizeStr := c.FormValue("max_size"); maxSizeStr != "" {
+			if customMax, err := core.ParseSize(maxSizeStr); err == nil && customMax > 0 {
+				opts.MaxSize = customMax
+			}
+		}
+
+		files, err := filesFromRequest(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, &goupload.UploadResult{
+				Success: false,
+				Message: "Failed to get files from request",
+				Error:   err.Error(),
+			})
+		}
+
+		result := core.Process(c.Request().Context(), files, opts)
+		return c.JSON(statusFor(result), result)
+	}
+}
+
+func filesFromRequest(c echo.Context) ([]*multipart.FileHeader, error) {
+	if form, err := c.MultipartForm(); err == nil {
+		if files, exists := form.File["files"]; exists && len(files) > 0 {
+			return files, nil
+		}
+		if files, exists := form.File["images"]; exists && len(files) > 0 {
+			return files, nil
+		}
+	}
+
+	if file, err := c.FormFile("file"); err == nil {
+		return []*multipart.FileHeader{file}, nil
+	}
+	if file, err := c.FormFile("image"); err == nil {
+		return []*multipart.FileHeader{file}, nil
+	}
+
+	return nil, fmt.Errorf("no files found in request")
+}
+
+func statusFor(result *goupload.UploadResult) int {
+	switch {
+	case result.Success:
+		return http.StatusOK
+	case result.QuotaExceeded:
+		return http.StatusRequestEntityTooLarge
+	case result.RateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusBadRequest
+	}
+}
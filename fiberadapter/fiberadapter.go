@@ -0,0 +1,70 @@
+// Package fiberadapter adapts a goupload.Core to a fiber.Handler.
+package fiberadapter
+
+import (
+	"fmt"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/Palguna1121/goupload"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler returns a fiber.Handler that runs uploads through core, reading
+// "sub_dir"/"max_size" form fields and "files"/"images"/"file"/"image"
+// file fields the same way goupload.ImageUploader does.
+func Handler(core *goupload.Core) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		opts := goupload.ProcessOptions{SubDir: c.FormValue("sub_dir"), Identity: c.IP()}
+		if maxSizeStr := c.FormValue("max_size"); maxSizeStr != "" {
+			if customMax, err := core.ParseSize(maxSizeStr); err == nil && customMax > 0 {
+				opts.MaxSize = customMax
+			}
+		}
+
+		files, err := filesFromRequest(c)
+		if err != nil {
+			return c.Status(http.StatusBadRequest).JSON(&goupload.UploadResult{
+				Success: false,
+				Message: "Failed to get files from request",
+				Error:   err.Error(),
+			})
+		}
+
+		result := core.Process(c.Context(), files, opts)
+		return c.Status(statusFor(result)).JSON(result)
+	}
+}
+
+func statusFor(result *goupload.UploadResult) int {
+	switch {
+	case result.Success:
+		return http.StatusOK
+	case result.QuotaExceeded:
+		return http.StatusRequestEntityTooLarge
+	case result.RateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+func filesFromRequest(c *fiber.Ctx) ([]*multipart.FileHeader, error) {
+	if form, err := c.MultipartForm(); err == nil {
+		if files, exists := form.File["files"]; exists && len(files) > 0 {
+			return files, nil
+		}
+		if files, exists := form.File["images"]; exists && len(files) > 0 {
+			return files, nil
+		}
+	}
+
+	if file, err := c.FormFile("file"); err == nil {
+		return []*multipart.FileHeader{file}, nil
+	}
+	if file, err := c.FormFile("image"); err == nil {
+		return []*multipart.FileHeader{file}, nil
+	}
+
+	return nil, fmt.Errorf("no files found in request")
+}
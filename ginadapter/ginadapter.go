@@ -0,0 +1,69 @@
+// Package ginadapter adapts a goupload.Core to a gin.HandlerFunc for
+// callers who want the framework-agnostic core without goupload's
+// Gin-specific ImageUploader type.
+package ginadapter
+
+import (
+	"fmt"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/Palguna1121/goupload"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler returns a gin.HandlerFunc that runs uploads through core,
+// reading "sub_dir"/"max_size" form fields and "files"/"images"/"file"/
+// "image" file fields the same way goupload.ImageUploader does.
+func Handler(core *goupload.Core) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		opts := goupload.ProcessOptions{SubDir: c.DefaultPostForm("sub_dir", ""), Identity: c.ClientIP()}
+		if maxSizeStr := c.DefaultPostForm("max_size", ""); maxSizeStr != "" {
+			if customMax, err := core.ParseSize(maxSizeStr); err == nil && customMax > 0 {
+				opts.MaxSize = customMax
+			}
+		}
+
+		files, err := filesFromRequest(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, &goupload.UploadResult{
+				Success: false,
+				Message: "Failed to get files from request",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		result := core.Process(c.Request.Context(), files, opts)
+		switch {
+		case result.Success:
+			c.JSON(http.StatusOK, result)
+		case result.QuotaExceeded:
+			c.JSON(http.StatusRequestEntityTooLarge, result)
+		case result.RateLimited:
+			c.JSON(http.StatusTooManyRequests, result)
+		default:
+			c.JSON(http.StatusBadRequest, result)
+		}
+	}
+}
+
+func filesFromRequest(c *gin.Context) ([]*multipart.FileHeader, error) {
+	if form, err := c.MultipartForm(); err == nil {
+		if files, exists := form.File["files"]; exists && len(files) > 0 {
+			return files, nil
+		}
+		if files, exists := form.File["images"]; exists && len(files) > 0 {
+			return files, nil
+		}
+	}
+
+	if file, err := c.FormFile("file"); err == nil {
+		return []*multipart.FileHeader{file}, nil
+	}
+	if file, err := c.FormFile("image"); err == nil {
+		return []*multipart.FileHeader{file}, nil
+	}
+
+	return nil, fmt.Errorf("no files found in request")
+}
@@ -0,0 +1,192 @@
+// Package httpadapter adapts a goupload.Core to a plain net/http.HandlerFunc,
+// for callers who don't want to pull in Gin (or any framework). It supports
+// both classic multipart uploads and raw-body PUT/POST uploads for single-
+// file clients that would rather not build a multipart body. Since the
+// returned handler has the standard http.HandlerFunc signature, it also
+// plugs directly into router.Method(pattern, httpadapter.Handler(core))
+// for net/http-compatible routers like Chi.
+package httpadapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/Palguna1121/goupload"
+)
+
+// Handler returns an http.HandlerFunc that runs uploads through core. It
+// accepts:
+//   - multipart/form-data POST/PUT requests, read via r.MultipartReader();
+//     file data beyond MaxFileSize bytes in total goes to temp files, the
+//     rest is held in memory
+//   - raw-body POST/PUT requests with Content-Type: application/octet-stream
+//     and an X-Filename header, for single-file clients
+func Handler(core *goupload.Core) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost && r.Method != http.MethodPut {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if r.Header.Get("Content-Type") == "application/octet-stream" {
+			handleRawBody(core, w, r)
+			return
+		}
+
+		handleMultipart(core, w, r)
+	}
+}
+
+func handleMultipart(core *goupload.Core, w http.ResponseWriter, r *http.Request) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		writeResult(w, http.StatusBadRequest, &goupload.UploadResult{
+			Success: false,
+			Message: "Failed to read multipart body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	form, err := reader.ReadForm(core.Config().MaxFileSize)
+	if err != nil {
+		writeResult(w, http.StatusBadRequest, &goupload.UploadResult{
+			Success: false,
+			Message: "Failed to read multipart form",
+			Error:   err.Error(),
+		})
+		return
+	}
+	defer form.RemoveAll()
+
+	files := filesFromForm(form)
+	if len(files) == 0 {
+		writeResult(w, http.StatusBadRequest, &goupload.UploadResult{
+			Success: false,
+			Message: "No files provided",
+		})
+		return
+	}
+
+	opts := goupload.ProcessOptions{SubDir: firstValue(form.Value["sub_dir"]), Identity: clientIP(r)}
+	if maxSizeStr := firstValue(form.Value["max_size"]); maxSizeStr != "" {
+		if customMax, err := core.ParseSize(maxSizeStr); err == nil && customMax > 0 {
+			opts.MaxSize = customMax
+		}
+	}
+
+	result := core.Process(r.Context(), files, opts)
+	writeResult(w, statusFor(result), result)
+}
+
+// handleRawBody supports PUT/POST with a raw image body instead of a
+// multipart form, identifying the file by the X-Filename header. The body
+// is staged to a temp file and run through Core.ProcessLocalFile, since
+// Core.Process requires a *multipart.FileHeader.
+func handleRawBody(core *goupload.Core, w http.ResponseWriter, r *http.Request) {
+	filename := r.Header.Get("X-Filename")
+	if filename == "" {
+		writeResult(w, http.StatusBadRequest, &goupload.UploadResult{
+			Success: false,
+			Message: "X-Filename header is required for raw-body uploads",
+		})
+		return
+	}
+
+	tmp, err := os.CreateTemp("", "goupload-raw-*")
+	if err != nil {
+		writeResult(w, http.StatusInternalServerError, &goupload.UploadResult{
+			Success: false,
+			Message: "Failed to stage upload",
+			Error:   err.Error(),
+		})
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := copyLimited(tmp, r.Body, core.Config().MaxFileSize); err != nil {
+		writeResult(w, http.StatusBadRequest, &goupload.UploadResult{
+			Success: false,
+			Message: "Failed to read request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	opts := goupload.ProcessOptions{SubDir: r.Header.Get("X-Sub-Dir"), Identity: clientIP(r)}
+	result, err := core.ProcessLocalFile(r.Context(), tmp.Name(), filename, opts)
+	if err != nil {
+		writeResult(w, http.StatusInternalServerError, &goupload.UploadResult{
+			Success: false,
+			Message: "Failed to process upload",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	writeResult(w, statusFor(result), result)
+}
+
+func filesFromForm(form *multipart.Form) []*multipart.FileHeader {
+	for _, field := range []string{"files", "images", "file", "image"} {
+		if files, ok := form.File[field]; ok && len(files) > 0 {
+			return files
+		}
+	}
+	return nil
+}
+
+func firstValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// copyLimited copies src to dst, reading one byte past limit so it can
+// distinguish "exactly limit bytes" from "more than limit bytes".
+func copyLimited(dst io.Writer, src io.Reader, limit int64) error {
+	n, err := io.Copy(dst, io.LimitReader(src, limit+1))
+	if err != nil {
+		return err
+	}
+	if n > limit {
+		return fmt.Errorf("body exceeds maximum size of %d bytes", limit)
+	}
+	return nil
+}
+
+// clientIP returns r's remote address with any port stripped, for use as
+// the default Quota/RateLimit identity.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func statusFor(result *goupload.UploadResult) int {
+	switch {
+	case result.Success:
+		return http.StatusOK
+	case result.QuotaExceeded:
+		return http.StatusRequestEntityTooLarge
+	case result.RateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+func writeResult(w http.ResponseWriter, status int, result *goupload.UploadResult) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,168 @@
+package goupload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProcessLocalFile runs the same validation, quota/rate-limit, storage, and
+// variant pipeline as Process against a file that already exists on local
+// disk, rather than a multipart.FileHeader from an HTTP request. It exists
+// for subsystems like resumable uploads and raw-body adapters that assemble
+// or stream a file before it can be validated and stored.
+func (c *Core) ProcessLocalFile(ctx context.Context, localPath, originalFilename string, opts ProcessOptions) (*UploadResult, error) {
+	// originalFilename may come from a caller-supplied header or metadata
+	// value (tus Upload-Metadata, a raw-body X-Filename header) rather than
+	// mime/multipart, which already strips any directory component from
+	// uploaded filenames. Base() it here so neither path can write outside
+	// the configured storage root.
+	originalFilename = filepath.Base(originalFilename)
+
+	if c.rateLimiter != nil && !c.rateLimiter.Allow(opts.Identity) {
+		return &UploadResult{
+			Success:     false,
+			Message:     "Rate limit exceeded",
+			RateLimited: true,
+		}, nil
+	}
+
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSize := c.config.MaxFileSize
+	if opts.MaxSize > 0 {
+		maxSize = opts.MaxSize
+	}
+
+	if c.config.Quota != nil {
+		if err := c.config.Quota.Reserve(ctx, opts.Identity, info.Size()); err != nil {
+			if errors.Is(err, ErrQuotaExceeded) {
+				return &UploadResult{
+					Success:       false,
+					Message:       fmt.Sprintf("Quota exceeded uploading %s", originalFilename),
+					QuotaExceeded: true,
+				}, nil
+			}
+			return nil, err
+		}
+	}
+
+	release := func() {
+		if c.config.Quota != nil {
+			c.config.Quota.Release(ctx, opts.Identity, info.Size())
+		}
+	}
+
+	if info.Size() > maxSize {
+		release()
+		return &UploadResult{
+			Success: false,
+			Message: fmt.Sprintf("file %s exceeds maximum size of %s", originalFilename, c.formatBytes(maxSize)),
+		}, nil
+	}
+
+	if !c.isAllowedExtension(originalFilename) {
+		release()
+		return &UploadResult{
+			Success: false,
+			Message: fmt.Sprintf("file %s has disallowed extension. Allowed: %s",
+				originalFilename, strings.Join(c.config.AllowedExtensions, ", ")),
+		}, nil
+	}
+
+	mime, err := detectMimeTypeFromPath(localPath)
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("failed to read MIME type for %s: %w", originalFilename, err)
+	}
+	if !c.isAllowedMimeType(mime) {
+		release()
+		return &UploadResult{
+			Success: false,
+			Message: fmt.Sprintf("file %s has disallowed MIME type: %s", originalFilename, mime),
+		}, nil
+	}
+
+	open := func() (io.ReadCloser, error) { return os.Open(localPath) }
+
+	var relativePath, hash string
+	if c.config.Deduplicate {
+		h, err := hashLocalFile(localPath)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		hash = h
+		relativePath = dedupKey(hash, filepath.Ext(originalFilename))
+	} else {
+		relativePath = c.buildRelativePath(opts.SubDir, c.generateFilename(originalFilename))
+	}
+
+	src, err := open()
+	if err != nil {
+		release()
+		return nil, err
+	}
+	fullURL, err := c.config.Storage.Put(ctx, relativePath, src, mime, c.config.Deduplicate)
+	src.Close()
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	result := &UploadResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Successfully uploaded %s", originalFilename),
+		FilePaths: []string{relativePath},
+		FileURLs:  []string{fullURL},
+	}
+	if c.config.Deduplicate {
+		result.Hashes = []string{hash}
+	}
+
+	if len(c.config.Variants) > 0 {
+		variants, err := generateVariants(ctx, c.config.Storage, originalFilename, open, relativePath, c.config.Variants)
+		if err != nil {
+			if !c.config.Deduplicate {
+				// relativePath is unique to this upload (not a shared dedup
+				// key), so remove the stored original rather than leave it
+				// orphaned while still holding its quota reservation.
+				if delErr := c.config.Storage.Delete(ctx, relativePath); delErr != nil {
+					return &UploadResult{
+						Success: false,
+						Message: fmt.Sprintf("Failed to generate variants for %s, and failed to clean up the stored original", originalFilename),
+						Error:   fmt.Sprintf("%v (variant error: %v)", delErr, err),
+					}, nil
+				}
+				release()
+			}
+			return &UploadResult{
+				Success: false,
+				Message: fmt.Sprintf("Failed to generate variants for %s", originalFilename),
+				Error:   err.Error(),
+			}, nil
+		}
+		if len(variants) > 0 {
+			result.Variants = map[string]map[string]string{originalFilename: variants}
+		}
+	}
+
+	return result, nil
+}
+
+func detectMimeTypeFromPath(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return SniffMIME(f)
+}
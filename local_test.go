@@ -0,0 +1,87 @@
+package goupload
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProcessLocalFileSanitizesFilenameTraversal(t *testing.T) {
+	storageDir := t.TempDir()
+	core := NewCore(UploadConfig{
+		AllowedExtensions: []string{"jpg"},
+		StoragePath:       storageDir,
+	})
+
+	srcDir := t.TempDir()
+	localPath := filepath.Join(srcDir, "staged")
+	jpegBytes := []byte{0xFF, 0xD8, 0xFF, 0xE0, 0, 0, 0, 0}
+	if err := os.WriteFile(localPath, jpegBytes, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	result, err := core.ProcessLocalFile(context.Background(), localPath, "../../../../tmp/evil.jpg", ProcessOptions{})
+	if err != nil {
+		t.Fatalf("ProcessLocalFile: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("ProcessLocalFile failed: %s (%s)", result.Message, result.Error)
+	}
+
+	for _, p := range result.FilePaths {
+		if strings.Contains(p, "..") {
+			t.Fatalf("stored relative path %q still contains a traversal segment", p)
+		}
+	}
+
+	entries, err := os.ReadDir(storageDir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected exactly one file written under storageDir, got %d entries", len(entries))
+	}
+}
+
+// TestProcessLocalFileVariantFailureReturnsStructuredResult mirrors
+// TestProcessFileCleansUpOrphanedOriginalOnVariantFailure for the local-file
+// path: a variant failure after Storage.Put must surface as a structured
+// UploadResult{Success:false} with a nil error, like every other validation
+// failure in this pipeline, not a bare Go error — callers like resumable's
+// complete() and httpadapter's handleRawBody check err != nil before they
+// ever look at result.Success.
+func TestProcessLocalFileVariantFailureReturnsStructuredResult(t *testing.T) {
+	storageDir := t.TempDir()
+	base := &FilesystemStorage{BasePath: storageDir}
+	storage := &failOnSubdirStorage{Storage: base, failSubdir: "thumb"}
+
+	core := NewCore(UploadConfig{
+		AllowedExtensions: []string{"jpg"},
+		Storage:           storage,
+		Variants:          []VariantSpec{{Name: "thumb", Width: 10, Height: 10, Fit: "cover", Format: "jpeg"}},
+	})
+
+	srcDir := t.TempDir()
+	localPath := filepath.Join(srcDir, "staged")
+	if err := os.WriteFile(localPath, encodeTestJPEG(t), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	result, err := core.ProcessLocalFile(context.Background(), localPath, "photo.jpg", ProcessOptions{})
+	if err != nil {
+		t.Fatalf("ProcessLocalFile should report a variant failure through the result, not a raw error: %v", err)
+	}
+	if result.Success {
+		t.Fatalf("expected ProcessLocalFile to fail when variant generation fails, got success")
+	}
+
+	entries, err := os.ReadDir(storageDir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected the stored original to be cleaned up after variant failure, found %d entries under storageDir", len(entries))
+	}
+}
@@ -0,0 +1,190 @@
+package goupload
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// sniffLen is how much of a file SniffMIME reads to match the magic-byte
+// signatures below and decide whether a file looks like an SVG at all.
+const sniffLen = 4096
+
+// maxSVGValidateSize bounds how much of a candidate SVG SniffMIME will read
+// to scan for <script>/event-handler XSS vectors. SVGs are XML text and
+// legitimate ones are rarely anywhere near this size, so it also doubles as
+// a sanity ceiling against pathological input.
+const maxSVGValidateSize = 5 * 1024 * 1024 // 5MB
+
+// magicSignature matches a fixed byte sequence at a fixed offset.
+type magicSignature struct {
+	offset int
+	magic  []byte
+	mime   string
+}
+
+var magicSignatures = []magicSignature{
+	{0, []byte{0xFF, 0xD8, 0xFF}, "image/jpeg"},
+	{0, []byte{0x89, 'P', 'N', 'G', 0x0D, 0x0A, 0x1A, 0x0A}, "image/png"},
+	{0, []byte("GIF87a"), "image/gif"},
+	{0, []byte("GIF89a"), "image/gif"},
+	{0, []byte("BM"), "image/bmp"},
+	{0, []byte{0xFF, 0x0A}, "image/jxl"},
+	{0, []byte{0x00, 0x00, 0x00, 0x0C, 'J', 'X', 'L', ' ', 0x0D, 0x0A, 0x87, 0x0A}, "image/jxl"},
+}
+
+// isoBMFFBrands maps the brand found in an ISO-BMFF "ftyp" box to a MIME
+// type, covering the HEIC/HEIF and AVIF family.
+var isoBMFFBrands = map[string]string{
+	"heic": "image/heic",
+	"heix": "image/heic",
+	"heim": "image/heic",
+	"heis": "image/heic",
+	"hevc": "image/heic",
+	"mif1": "image/heif",
+	"avif": "image/avif",
+	"avis": "image/avif",
+}
+
+// SniffMIME identifies the MIME type of r's content by matching known file
+// signatures ("magic bytes") rather than relying on http.DetectContentType,
+// which misses WebP on some Go versions and doesn't recognize HEIC/AVIF at
+// all. SVGs are parsed as XML and rejected if they look like a stored-XSS
+// vector; since that vector could appear anywhere in the document, candidate
+// SVGs are read in full (up to maxSVGValidateSize) rather than just the
+// sniffLen window used to recognize the other formats. r is read up to
+// sniffLen bytes for non-SVG content, or up to maxSVGValidateSize for SVG
+// content; the caller is responsible for re-reading/seeking the underlying
+// source afterwards if needed.
+func SniffMIME(r io.Reader) (string, error) {
+	buf := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	buf = buf[:n]
+
+	for _, sig := range magicSignatures {
+		if matchesSignature(buf, sig) {
+			return sig.mime, nil
+		}
+	}
+
+	if mime, ok := matchWebP(buf); ok {
+		return mime, nil
+	}
+
+	if mime, ok := matchISOBMFF(buf); ok {
+		return mime, nil
+	}
+
+	if looksLikeXML(buf) {
+		full, err := readFullSVG(buf, r)
+		if err != nil {
+			return "", err
+		}
+		if err := validateSVG(full); err != nil {
+			return "", err
+		}
+		return "image/svg+xml", nil
+	}
+
+	return "application/octet-stream", nil
+}
+
+// readFullSVG returns buf plus the rest of r, so validateSVG scans the
+// entire candidate SVG instead of just the sniffLen window a <script> or
+// event handler could sit past. Documents over maxSVGValidateSize are
+// rejected outright rather than validated against a truncated read, which
+// would silently treat "too big to scan" as "safe".
+func readFullSVG(buf []byte, r io.Reader) ([]byte, error) {
+	rest, err := io.ReadAll(io.LimitReader(r, maxSVGValidateSize-int64(len(buf))+1))
+	if err != nil {
+		return nil, err
+	}
+	full := append(buf, rest...)
+	if len(full) > maxSVGValidateSize {
+		return nil, fmt.Errorf("svg exceeds maximum size of %d bytes allowed for content validation", maxSVGValidateSize)
+	}
+	return full, nil
+}
+
+func matchesSignature(buf []byte, sig magicSignature) bool {
+	end := sig.offset + len(sig.magic)
+	if end > len(buf) {
+		return false
+	}
+	return bytes.Equal(buf[sig.offset:end], sig.magic)
+}
+
+// matchWebP checks for a RIFF container with a WEBP payload: "RIFF", a
+// 4-byte little-endian size (ignored), then "WEBP".
+func matchWebP(buf []byte) (string, bool) {
+	if len(buf) < 12 {
+		return "", false
+	}
+	if string(buf[0:4]) == "RIFF" && string(buf[8:12]) == "WEBP" {
+		return "image/webp", true
+	}
+	return "", false
+}
+
+// matchISOBMFF checks for an ISO-BMFF "ftyp" box (used by HEIC/HEIF/AVIF)
+// and maps its major brand to a MIME type.
+func matchISOBMFF(buf []byte) (string, bool) {
+	if len(buf) < 12 {
+		return "", false
+	}
+	if string(buf[4:8]) != "ftyp" {
+		return "", false
+	}
+	brand := strings.TrimRight(string(buf[8:12]), "\x00")
+	mime, ok := isoBMFFBrands[brand]
+	return mime, ok
+}
+
+func looksLikeXML(buf []byte) bool {
+	trimmed := bytes.TrimLeft(buf, " \t\r\n")
+	return bytes.HasPrefix(trimmed, []byte("<?xml")) || bytes.HasPrefix(trimmed, []byte("<svg"))
+}
+
+// validateSVG parses buf — the complete candidate document, per SniffMIME —
+// as XML looking for <script> elements or "on*" event handler attributes,
+// either of which would let an uploaded SVG run script in the context of
+// whoever views it.
+func validateSVG(buf []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(buf))
+	decoder.Strict = false
+
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			// buf is the whole document, so a parse error here means
+			// malformed XML, not a truncated read — reject rather than
+			// risk a crafted parse error hiding a <script> tag past it.
+			return fmt.Errorf("svg is not well-formed XML: %w", err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if strings.EqualFold(start.Name.Local, "script") {
+			return fmt.Errorf("svg contains a <script> element")
+		}
+
+		for _, attr := range start.Attr {
+			if strings.HasPrefix(strings.ToLower(attr.Name.Local), "on") {
+				return fmt.Errorf("svg contains event handler attribute %q", attr.Name.Local)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package goupload
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSniffMIMEMagicSignatures(t *testing.T) {
+	cases := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"jpeg", []byte{0xFF, 0xD8, 0xFF, 0xE0, 0, 0, 0, 0}, "image/jpeg"},
+		{"png", []byte{0x89, 'P', 'N', 'G', 0x0D, 0x0A, 0x1A, 0x0A}, "image/png"},
+		{"gif", []byte("GIF89a"), "image/gif"},
+		{"unknown", []byte{0x01, 0x02, 0x03}, "application/octet-stream"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mime, err := SniffMIME(bytes.NewReader(c.data))
+			if err != nil {
+				t.Fatalf("SniffMIME: %v", err)
+			}
+			if mime != c.want {
+				t.Errorf("SniffMIME(%s) = %q, want %q", c.name, mime, c.want)
+			}
+		})
+	}
+}
+
+func TestSniffMIMERejectsSVGScriptPastSniffWindow(t *testing.T) {
+	padding := strings.Repeat(" ", sniffLen+1024)
+	svg := "<svg xmlns=\"http://www.w3.org/2000/svg\"><!--" + padding + "--><script>alert(1)</script></svg>"
+
+	_, err := SniffMIME(strings.NewReader(svg))
+	if err == nil {
+		t.Fatal("SniffMIME accepted an SVG whose <script> element sits past the sniff window")
+	}
+}
+
+func TestSniffMIMEAcceptsCleanLargeSVG(t *testing.T) {
+	padding := strings.Repeat(" ", sniffLen+1024)
+	svg := "<svg xmlns=\"http://www.w3.org/2000/svg\"><!--" + padding + "--><rect width=\"1\" height=\"1\"/></svg>"
+
+	mime, err := SniffMIME(strings.NewReader(svg))
+	if err != nil {
+		t.Fatalf("SniffMIME rejected a clean SVG: %v", err)
+	}
+	if mime != "image/svg+xml" {
+		t.Errorf("SniffMIME = %q, want image/svg+xml", mime)
+	}
+}
+
+func TestSniffMIMERejectsOversizedSVG(t *testing.T) {
+	padding := strings.Repeat(" ", maxSVGValidateSize+1024)
+	svg := "<svg xmlns=\"http://www.w3.org/2000/svg\"><!--" + padding + "--></svg>"
+
+	_, err := SniffMIME(strings.NewReader(svg))
+	if err == nil {
+		t.Fatal("SniffMIME accepted an SVG larger than maxSVGValidateSize")
+	}
+}
+
+func TestSniffMIMERejectsEventHandlerAttribute(t *testing.T) {
+	svg := `<svg xmlns="http://www.w3.org/2000/svg"><rect onload="alert(1)"/></svg>`
+
+	_, err := SniffMIME(strings.NewReader(svg))
+	if err == nil {
+		t.Fatal("SniffMIME accepted an SVG with an onload event handler attribute")
+	}
+}
@@ -0,0 +1,64 @@
+package goupload
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrQuotaExceeded is returned by QuotaStore.Reserve when userID doesn't
+// have enough quota left for the reservation.
+var ErrQuotaExceeded = errors.New("goupload: quota exceeded")
+
+// QuotaStore tracks how many bytes each identity (user, API key, IP, ...)
+// has stored, so Process can refuse uploads once a caller-defined limit is
+// reached.
+type QuotaStore interface {
+	Reserve(ctx context.Context, userID string, bytes int64) error
+	Release(ctx context.Context, userID string, bytes int64) error
+	Usage(ctx context.Context, userID string) (used, limit int64, err error)
+}
+
+// MemoryQuotaStore is an in-memory QuotaStore applying the same byte limit
+// to every identity. Usage does not survive a process restart.
+type MemoryQuotaStore struct {
+	Limit int64
+
+	mu    sync.Mutex
+	usage map[string]int64
+}
+
+// NewMemoryQuotaStore returns a MemoryQuotaStore capping each identity at
+// limit bytes.
+func NewMemoryQuotaStore(limit int64) *MemoryQuotaStore {
+	return &MemoryQuotaStore{Limit: limit, usage: make(map[string]int64)}
+}
+
+func (s *MemoryQuotaStore) Reserve(ctx context.Context, userID string, bytes int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.usage[userID]+bytes > s.Limit {
+		return ErrQuotaExceeded
+	}
+	s.usage[userID] += bytes
+	return nil
+}
+
+func (s *MemoryQuotaStore) Release(ctx context.Context, userID string, bytes int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.usage[userID] -= bytes
+	if s.usage[userID] < 0 {
+		s.usage[userID] = 0
+	}
+	return nil
+}
+
+func (s *MemoryQuotaStore) Usage(ctx context.Context, userID string) (int64, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.usage[userID], s.Limit, nil
+}
@@ -0,0 +1,57 @@
+package goupload
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisQuotaStore is a QuotaStore backed by Redis, for deployments running
+// more than one ImageUploader/Core instance against a shared quota.
+type RedisQuotaStore struct {
+	Client    *redis.Client
+	Limit     int64
+	KeyPrefix string // defaults to "goupload:quota:" when empty
+}
+
+// NewRedisQuotaStore returns a RedisQuotaStore capping each identity at
+// limit bytes.
+func NewRedisQuotaStore(client *redis.Client, limit int64) *RedisQuotaStore {
+	return &RedisQuotaStore{Client: client, Limit: limit, KeyPrefix: "goupload:quota:"}
+}
+
+func (s *RedisQuotaStore) key(userID string) string {
+	if s.KeyPrefix == "" {
+		return "goupload:quota:" + userID
+	}
+	return s.KeyPrefix + userID
+}
+
+func (s *RedisQuotaStore) Reserve(ctx context.Context, userID string, bytes int64) error {
+	used, err := s.Client.IncrBy(ctx, s.key(userID), bytes).Result()
+	if err != nil {
+		return fmt.Errorf("goupload: redis quota reserve: %w", err)
+	}
+
+	if used > s.Limit {
+		s.Client.DecrBy(ctx, s.key(userID), bytes)
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+func (s *RedisQuotaStore) Release(ctx context.Context, userID string, bytes int64) error {
+	if err := s.Client.DecrBy(ctx, s.key(userID), bytes).Err(); err != nil {
+		return fmt.Errorf("goupload: redis quota release: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisQuotaStore) Usage(ctx context.Context, userID string) (int64, int64, error) {
+	used, err := s.Client.Get(ctx, s.key(userID)).Int64()
+	if err != nil && err != redis.Nil {
+		return 0, s.Limit, fmt.Errorf("goupload: redis quota usage: %w", err)
+	}
+	return used, s.Limit, nil
+}
@@ -0,0 +1,54 @@
+package goupload
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter is an in-memory token-bucket limiter keyed by identity,
+// refilling ratePerMinute tokens every minute. It's used to cap uploads
+// per minute per identity alongside QuotaStore's byte accounting.
+type RateLimiter struct {
+	ratePerMinute int
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing ratePerMinute uploads per
+// minute for each identity.
+func NewRateLimiter(ratePerMinute int) *RateLimiter {
+	return &RateLimiter{ratePerMinute: ratePerMinute, buckets: make(map[string]*tokenBucket)}
+}
+
+// Allow reports whether identity has an upload token available, consuming
+// one if so.
+func (l *RateLimiter) Allow(identity string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	b, ok := l.buckets[identity]
+	if !ok {
+		b = &tokenBucket{tokens: float64(l.ratePerMinute), lastRefill: now}
+		l.buckets[identity] = b
+	} else {
+		b.tokens += now.Sub(b.lastRefill).Minutes() * float64(l.ratePerMinute)
+		if b.tokens > float64(l.ratePerMinute) {
+			b.tokens = float64(l.ratePerMinute)
+		}
+		b.lastRefill = now
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
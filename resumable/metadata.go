@@ -0,0 +1,94 @@
+package resumable
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// MetadataStore persists per-upload bookkeeping (offset, total length,
+// original filename, etc.) across the multiple requests a resumable
+// upload spans.
+type MetadataStore interface {
+	Create(ctx context.Context, upload *Upload) error
+	Get(ctx context.Context, id string) (*Upload, error)
+	UpdateOffset(ctx context.Context, id string, offset int64) error
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryMetadataStore is an in-memory MetadataStore, suitable for a single
+// instance or for tests. Uploads do not survive a process restart.
+type MemoryMetadataStore struct {
+	mu      sync.Mutex
+	uploads map[string]*Upload
+}
+
+// NewMemoryMetadataStore returns an empty MemoryMetadataStore.
+func NewMemoryMetadataStore() *MemoryMetadataStore {
+	return &MemoryMetadataStore{uploads: make(map[string]*Upload)}
+}
+
+func (s *MemoryMetadataStore) Create(ctx context.Context, upload *Upload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp := *upload
+	s.uploads[upload.ID] = &cp
+	return nil
+}
+
+func (s *MemoryMetadataStore) Get(ctx context.Context, id string) (*Upload, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	upload, ok := s.uploads[id]
+	if !ok {
+		return nil, fmt.Errorf("resumable: unknown upload %q", id)
+	}
+	cp := *upload
+	return &cp, nil
+}
+
+func (s *MemoryMetadataStore) UpdateOffset(ctx context.Context, id string, offset int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	upload, ok := s.uploads[id]
+	if !ok {
+		return fmt.Errorf("resumable: unknown upload %q", id)
+	}
+	upload.Offset = offset
+	return nil
+}
+
+func (s *MemoryMetadataStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.uploads[id]; !ok {
+		return fmt.Errorf("resumable: unknown upload %q", id)
+	}
+	delete(s.uploads, id)
+	return nil
+}
+
+// parseUploadMetadata decodes a tus Upload-Metadata header value, a
+// comma-separated list of "key base64(value)" pairs.
+func parseUploadMetadata(header string) map[string]string {
+	result := make(map[string]string)
+	if header == "" {
+		return result
+	}
+
+	for _, pair := range strings.Split(header, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), " ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		value, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			continue
+		}
+		result[parts[0]] = string(value)
+	}
+
+	return result
+}
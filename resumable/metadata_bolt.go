@@ -0,0 +1,86 @@
+package resumable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var uploadsBucket = []byte("uploads")
+
+// BoltMetadataStore is a MetadataStore backed by a BoltDB file, for
+// single-instance deployments that need uploads to survive a restart.
+type BoltMetadataStore struct {
+	db *bolt.DB
+}
+
+// NewBoltMetadataStore opens (creating if necessary) a BoltDB database at
+// path and prepares it for use as a MetadataStore.
+func NewBoltMetadataStore(path string) (*BoltMetadataStore, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("resumable: opening bolt db: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(uploadsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltMetadataStore{db: db}, nil
+}
+
+// Close releases the underlying BoltDB file handle.
+func (s *BoltMetadataStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *BoltMetadataStore) Create(ctx context.Context, upload *Upload) error {
+	return s.put(upload)
+}
+
+func (s *BoltMetadataStore) Get(ctx context.Context, id string) (*Upload, error) {
+	var upload Upload
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(uploadsBucket).Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("resumable: unknown upload %q", id)
+		}
+		return json.Unmarshal(data, &upload)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+func (s *BoltMetadataStore) UpdateOffset(ctx context.Context, id string, offset int64) error {
+	upload, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	upload.Offset = offset
+	return s.put(upload)
+}
+
+func (s *BoltMetadataStore) Delete(ctx context.Context, id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(uploadsBucket).Delete([]byte(id))
+	})
+}
+
+func (s *BoltMetadataStore) put(upload *Upload) error {
+	data, err := json.Marshal(upload)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(uploadsBucket).Put([]byte(upload.ID), data)
+	})
+}
@@ -0,0 +1,296 @@
+// Package resumable implements the tus.io v1.0.0 resumable upload protocol
+// (https://tus.io/protocols/resumable-upload) on top of a goupload.Storage
+// backend, for uploads too large or too slow to fit in a single request.
+package resumable
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Palguna1121/goupload"
+	"github.com/google/uuid"
+)
+
+// TusVersion is the protocol version this package implements.
+const TusVersion = "1.0.0"
+
+// ErrOffsetMismatch is returned when a PATCH request's Upload-Offset header
+// doesn't match the upload's current offset, per the tus protocol.
+var ErrOffsetMismatch = errors.New("resumable: upload offset mismatch")
+
+// Upload tracks one in-progress (or completed) resumable upload.
+type Upload struct {
+	ID        string
+	TotalSize int64
+	Offset    int64
+	Filename  string
+	MIME      string
+	SubDir    string
+	ExpiresAt time.Time
+}
+
+// Done reports whether all bytes for this upload have been received.
+func (u *Upload) Done() bool {
+	return u.Offset >= u.TotalSize
+}
+
+// Handler implements the tus.io creation, core, and termination extensions.
+// Chunks are staged under StagingDir, keyed by upload ID, and moved into
+// Core's storage once an upload completes.
+type Handler struct {
+	Core       *goupload.Core
+	Metadata   MetadataStore
+	StagingDir string
+	Expiry     time.Duration // Create sets each upload's ExpiresAt to its creation time plus Expiry; expired uploads are not removed by this package
+
+	// IdentifyUser resolves the identity Core's Quota and RateLimit use for
+	// this upload. Defaults to the client's IP address.
+	IdentifyUser func(r *http.Request) string
+}
+
+// NewHandler returns a Handler that assembles chunks in stagingDir and
+// hands completed uploads to core.
+func NewHandler(core *goupload.Core, metadata MetadataStore, stagingDir string) *Handler {
+	return &Handler{
+		Core:         core,
+		Metadata:     metadata,
+		StagingDir:   stagingDir,
+		Expiry:       24 * time.Hour,
+		IdentifyUser: clientIP,
+	}
+}
+
+// clientIP returns r's remote address with any port stripped.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// RegisterRoutes wires the tus endpoints onto mux under basePath (default
+// "/files"): POST creates an upload, HEAD/PATCH drive it, DELETE terminates
+// it early.
+func (h *Handler) RegisterRoutes(mux *http.ServeMux, basePath string) {
+	if basePath == "" {
+		basePath = "/files"
+	}
+	basePath = "/" + strings.Trim(basePath, "/")
+
+	mux.HandleFunc(basePath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			h.Create(w, r)
+			return
+		}
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	})
+
+	mux.HandleFunc(basePath+"/", func(w http.ResponseWriter, r *http.Request) {
+		id := filepath.Base(r.URL.Path)
+		switch r.Method {
+		case http.MethodHead:
+			h.Head(w, r, id)
+		case http.MethodPatch:
+			h.Patch(w, r, id)
+		case http.MethodDelete:
+			h.Delete(w, r, id)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// Create handles POST /files: it allocates an upload ID and returns its
+// location. Upload-Length and Upload-Metadata follow the tus creation
+// extension; Upload-Metadata entries are "key base64(value)" pairs, of
+// which this handler reads "filename" and "subdir".
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	totalSize, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || totalSize < 0 {
+		http.Error(w, "invalid or missing Upload-Length", http.StatusBadRequest)
+		return
+	}
+
+	meta := parseUploadMetadata(r.Header.Get("Upload-Metadata"))
+
+	id := uuid.NewString()
+	upload := &Upload{
+		ID:        id,
+		TotalSize: totalSize,
+		Filename:  meta["filename"],
+		SubDir:    meta["subdir"],
+		ExpiresAt: time.Now().Add(h.Expiry),
+	}
+
+	if err := os.MkdirAll(h.StagingDir, 0755); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := os.WriteFile(h.stagingPath(id), nil, 0644); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.Metadata.Create(r.Context(), upload); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Location", r.URL.String()+"/"+id)
+	w.Header().Set("Tus-Resumable", TusVersion)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// Head handles HEAD /files/{id}, reporting how many bytes have been
+// received so the client knows where to resume from.
+func (h *Handler) Head(w http.ResponseWriter, r *http.Request, id string) {
+	upload, err := h.Metadata.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, "upload not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(upload.TotalSize, 10))
+	w.Header().Set("Tus-Resumable", TusVersion)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+}
+
+// Patch handles PATCH /files/{id}: it appends the request body at
+// Upload-Offset and, once the upload is complete, hands the assembled
+// file to the configured Core.
+func (h *Handler) Patch(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Header.Get("Content-Type") != "application/offset+octet-stream" {
+		http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid Upload-Offset", http.StatusBadRequest)
+		return
+	}
+
+	upload, err := h.Metadata.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, "upload not found", http.StatusNotFound)
+		return
+	}
+	if offset != upload.Offset {
+		http.Error(w, ErrOffsetMismatch.Error(), http.StatusConflict)
+		return
+	}
+
+	written, err := h.appendChunk(id, offset, r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	newOffset := offset + written
+	if err := h.Metadata.UpdateOffset(r.Context(), id, newOffset); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	upload.Offset = newOffset
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(newOffset, 10))
+	w.Header().Set("Tus-Resumable", TusVersion)
+
+	if upload.Done() {
+		result, err := h.complete(r, upload)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !result.Success {
+			http.Error(w, completionFailureMessage(result), completionFailureStatus(result))
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Delete handles DELETE /files/{id}, the termination extension: it removes
+// the staged chunks and metadata for an incomplete upload.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request, id string) {
+	if err := h.Metadata.Delete(r.Context(), id); err != nil {
+		http.Error(w, "upload not found", http.StatusNotFound)
+		return
+	}
+	os.Remove(h.stagingPath(id))
+
+	w.Header().Set("Tus-Resumable", TusVersion)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) stagingPath(id string) string {
+	return filepath.Join(h.StagingDir, id)
+}
+
+func (h *Handler) appendChunk(id string, offset int64, body io.Reader) (int64, error) {
+	f, err := os.OpenFile(h.stagingPath(id), os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	return io.Copy(f, body)
+}
+
+// complete runs the assembled file through the same validate-store-variant
+// pipeline ProcessUpload uses, and removes the staged copy only once that
+// pipeline actually accepted the file. Callers must check result.Success:
+// ProcessLocalFile reports rejections (oversized file, disallowed
+// extension/MIME type, quota) through the result rather than the error
+// return, and an unchecked result here would make Patch tell the client an
+// upload succeeded when nothing was stored.
+func (h *Handler) complete(r *http.Request, upload *Upload) (*goupload.UploadResult, error) {
+	opts := goupload.ProcessOptions{SubDir: upload.SubDir, Identity: h.IdentifyUser(r)}
+	result, err := h.Core.ProcessLocalFile(r.Context(), h.stagingPath(upload.ID), upload.Filename, opts)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return result, nil
+	}
+	if err := os.Remove(h.stagingPath(upload.ID)); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// completionFailureStatus maps a failed completion's UploadResult to the
+// HTTP status Patch should report.
+func completionFailureStatus(result *goupload.UploadResult) int {
+	switch {
+	case result.QuotaExceeded:
+		return http.StatusRequestEntityTooLarge
+	case result.RateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+func completionFailureMessage(result *goupload.UploadResult) string {
+	if result.Error != "" {
+		return fmt.Sprintf("%s: %s", result.Message, result.Error)
+	}
+	return result.Message
+}
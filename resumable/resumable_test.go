@@ -0,0 +1,95 @@
+package resumable
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Palguna1121/goupload"
+)
+
+func newTestHandler(t *testing.T, maxFileSize int64) *Handler {
+	t.Helper()
+
+	stagingDir := t.TempDir()
+	storageDir := t.TempDir()
+
+	core := goupload.NewCore(goupload.UploadConfig{
+		MaxFileSize:       maxFileSize,
+		AllowedExtensions: []string{"jpg"},
+		StoragePath:       storageDir,
+	})
+	return NewHandler(core, NewMemoryMetadataStore(), stagingDir)
+}
+
+func createTestUpload(t *testing.T, h *Handler, totalSize int64, filename string) string {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/files", nil)
+	req.Header.Set("Upload-Length", strconv.FormatInt(totalSize, 10))
+	req.Header.Set("Upload-Metadata", "filename "+base64.StdEncoding.EncodeToString([]byte(filename)))
+
+	rec := httptest.NewRecorder()
+	h.Create(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Create: got status %d, body %q", rec.Code, rec.Body.String())
+	}
+
+	location := rec.Header().Get("Location")
+	return location[strings.LastIndex(location, "/")+1:]
+}
+
+// TestPatchSurfacesCompletionFailure covers resumable.go's Patch/complete:
+// an assembled upload that exceeds MaxFileSize must be reported back to the
+// client as a failure, not silently discarded as a 204 No Content.
+func TestPatchSurfacesCompletionFailure(t *testing.T) {
+	h := newTestHandler(t, 1) // any non-empty upload exceeds this
+	body := "hello world"
+	id := createTestUpload(t, h, int64(len(body)), "hello.jpg")
+
+	patchReq := httptest.NewRequest(http.MethodPatch, "/files/"+id, strings.NewReader(body))
+	patchReq.Header.Set("Content-Type", "application/offset+octet-stream")
+	patchReq.Header.Set("Upload-Offset", "0")
+
+	rec := httptest.NewRecorder()
+	h.Patch(rec, patchReq, id)
+
+	if rec.Code == http.StatusNoContent {
+		t.Fatalf("Patch reported success (204) for an upload exceeding MaxFileSize")
+	}
+	if rec.Code < 400 {
+		t.Fatalf("Patch: got status %d, want a failure status", rec.Code)
+	}
+
+	if _, err := h.Metadata.Get(context.Background(), id); err != nil {
+		t.Fatalf("metadata for a failed completion should still be retrievable, got: %v", err)
+	}
+}
+
+// TestPatchCompletesSuccessfully is the control case: a valid upload within
+// limits should complete and clean up its staged copy.
+func TestPatchCompletesSuccessfully(t *testing.T) {
+	h := newTestHandler(t, 1<<20)
+	body := []byte{0xFF, 0xD8, 0xFF, 0xE0, 0, 0, 0, 0} // enough of a JPEG magic-byte prefix to sniff as image/jpeg
+	id := createTestUpload(t, h, int64(len(body)), "photo.jpg")
+
+	patchReq := httptest.NewRequest(http.MethodPatch, "/files/"+id, strings.NewReader(string(body)))
+	patchReq.Header.Set("Content-Type", "application/offset+octet-stream")
+	patchReq.Header.Set("Upload-Offset", "0")
+
+	rec := httptest.NewRecorder()
+	h.Patch(rec, patchReq, id)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Patch: got status %d, body %q, want 204", rec.Code, rec.Body.String())
+	}
+
+	if _, err := os.Stat(h.stagingPath(id)); !os.IsNotExist(err) {
+		t.Fatalf("staged chunk file should be removed after a successful completion")
+	}
+}
@@ -0,0 +1,167 @@
+package goupload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Storage abstracts where uploaded files end up so ImageUploader doesn't
+// have to know whether it's writing to local disk, S3, or GCS.
+type Storage interface {
+	// Put writes reader to key and returns the URL clients should use to
+	// fetch it (a CDN/presigned URL for remote backends, a BaseURL +
+	// "/storage/..." URL for the filesystem backend). When skipIfExists is
+	// true and key already exists, Put leaves the existing object alone and
+	// just returns its URL; callers should only set this for content-
+	// addressed keys (Core's Deduplicate mode), where an existing object at
+	// key is guaranteed to already hold the same bytes. For any other key
+	// (generated filenames, caller-controlled paths), skipIfExists must be
+	// false, or a second upload that happens to collide on the same key
+	// silently discards its content instead of overwriting it.
+	Put(ctx context.Context, key string, reader io.Reader, contentType string, skipIfExists bool) (url string, err error)
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	Exists(ctx context.Context, key string) (bool, error)
+}
+
+// NewStorage builds a Storage backend from a driver name and a DSN, e.g.
+//
+//	NewStorage("filesystem", "file://storage/app/public/uploads/images?base_url=http://localhost:5220")
+//	NewStorage("s3", "s3://my-bucket/uploads?region=us-east-1&cdn=https://cdn.example.com")
+//	NewStorage("gcs", "gcs://my-bucket/uploads?cdn=https://cdn.example.com")
+func NewStorage(driver, sourceDSN string) (Storage, error) {
+	parsed, err := url.Parse(sourceDSN)
+	if err != nil {
+		return nil, fmt.Errorf("goupload: invalid storage DSN %q: %w", sourceDSN, err)
+	}
+
+	switch strings.ToLower(driver) {
+	case "filesystem", "file", "":
+		basePath := parsed.Host + parsed.Path
+		if basePath == "" {
+			basePath = "storage/app/public/uploads/images"
+		}
+		return &FilesystemStorage{
+			BasePath: basePath,
+			BaseURL:  parsed.Query().Get("base_url"),
+		}, nil
+	case "s3":
+		prefix := strings.TrimPrefix(parsed.Path, "/")
+		s3Storage := &S3Storage{
+			Bucket:     parsed.Host,
+			Prefix:     prefix,
+			Region:     parsed.Query().Get("region"),
+			CDNBaseURL: parsed.Query().Get("cdn"),
+		}
+		if s3Storage.Bucket == "" {
+			return nil, fmt.Errorf("goupload: s3 DSN %q missing bucket", sourceDSN)
+		}
+		if err := s3Storage.init(); err != nil {
+			return nil, err
+		}
+		return s3Storage, nil
+	case "gcs":
+		prefix := strings.TrimPrefix(parsed.Path, "/")
+		gcsStorage := &GCSStorage{
+			Bucket:     parsed.Host,
+			Prefix:     prefix,
+			CDNBaseURL: parsed.Query().Get("cdn"),
+		}
+		if gcsStorage.Bucket == "" {
+			return nil, fmt.Errorf("goupload: gcs DSN %q missing bucket", sourceDSN)
+		}
+		if err := gcsStorage.init(); err != nil {
+			return nil, err
+		}
+		return gcsStorage, nil
+	default:
+		return nil, fmt.Errorf("goupload: unknown storage driver %q", driver)
+	}
+}
+
+// FilesystemStorage stores files on local disk and serves them from
+// BaseURL + "/storage/" + key, matching the module's original behavior.
+type FilesystemStorage struct {
+	BasePath string
+	BaseURL  string
+}
+
+func (s *FilesystemStorage) Put(ctx context.Context, key string, reader io.Reader, contentType string, skipIfExists bool) (string, error) {
+	fullPath := filepath.Join(s.BasePath, filepath.FromSlash(key))
+
+	if skipIfExists {
+		if _, err := os.Stat(fullPath); err == nil {
+			return s.url(key), nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return "", err
+	}
+
+	f, err := os.Create(fullPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, reader); err != nil {
+		return "", err
+	}
+
+	return s.url(key), nil
+}
+
+func (s *FilesystemStorage) url(key string) string {
+	baseURL := strings.TrimRight(s.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = "http://localhost:5220"
+	}
+	return fmt.Sprintf("%s/storage/%s", baseURL, strings.TrimLeft(key, "/"))
+}
+
+func (s *FilesystemStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(s.BasePath, filepath.FromSlash(key)))
+}
+
+func (s *FilesystemStorage) Delete(ctx context.Context, key string) error {
+	return os.Remove(filepath.Join(s.BasePath, filepath.FromSlash(key)))
+}
+
+func (s *FilesystemStorage) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := os.Stat(filepath.Join(s.BasePath, filepath.FromSlash(key)))
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// buildKey joins prefix and key the way all remote backends expect.
+func buildKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return strings.TrimRight(prefix, "/") + "/" + strings.TrimLeft(key, "/")
+}
+
+// parseBoolQuery parses v as a bool, returning def when v is empty or not a
+// valid bool.
+func parseBoolQuery(v string, def bool) bool {
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return def
+	}
+	return b
+}
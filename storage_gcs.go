@@ -0,0 +1,79 @@
+package goupload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"cloud.google.com/go/storage"
+)
+
+// GCSStorage stores files in a Google Cloud Storage bucket and serves them
+// from CDNBaseURL when set, falling back to the public GCS object URL.
+type GCSStorage struct {
+	Bucket     string
+	Prefix     string
+	CDNBaseURL string
+
+	client *storage.Client
+}
+
+func (s *GCSStorage) init() error {
+	client, err := storage.NewClient(context.Background())
+	if err != nil {
+		return fmt.Errorf("goupload: creating GCS client: %w", err)
+	}
+	s.client = client
+	return nil
+}
+
+func (s *GCSStorage) Put(ctx context.Context, key string, reader io.Reader, contentType string, skipIfExists bool) (string, error) {
+	objectKey := buildKey(s.Prefix, key)
+
+	if skipIfExists {
+		if exists, err := s.Exists(ctx, key); err == nil && exists {
+			return s.url(objectKey), nil
+		}
+	}
+
+	w := s.client.Bucket(s.Bucket).Object(objectKey).NewWriter(ctx)
+	w.ContentType = contentType
+
+	if _, err := io.Copy(w, reader); err != nil {
+		w.Close()
+		return "", fmt.Errorf("goupload: gcs put %s: %w", objectKey, err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("goupload: gcs put %s: %w", objectKey, err)
+	}
+
+	return s.url(objectKey), nil
+}
+
+func (s *GCSStorage) url(objectKey string) string {
+	if s.CDNBaseURL != "" {
+		return strings.TrimRight(s.CDNBaseURL, "/") + "/" + objectKey
+	}
+	return fmt.Sprintf("https://storage.googleapis.com/%s/%s", s.Bucket, objectKey)
+}
+
+func (s *GCSStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.client.Bucket(s.Bucket).Object(buildKey(s.Prefix, key)).NewReader(ctx)
+}
+
+func (s *GCSStorage) Delete(ctx context.Context, key string) error {
+	return s.client.Bucket(s.Bucket).Object(buildKey(s.Prefix, key)).Delete(ctx)
+}
+
+func (s *GCSStorage) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := s.client.Bucket(s.Bucket).Object(buildKey(s.Prefix, key)).Attrs(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
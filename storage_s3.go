@@ -0,0 +1,97 @@
+package goupload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Storage stores files in an S3 bucket and serves them from CDNBaseURL
+// when set, falling back to the bucket's virtual-hosted-style URL.
+type S3Storage struct {
+	Bucket     string
+	Prefix     string
+	Region     string
+	CDNBaseURL string
+
+	client *s3.Client
+}
+
+func (s *S3Storage) init() error {
+	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(s.Region))
+	if err != nil {
+		return fmt.Errorf("goupload: loading AWS config: %w", err)
+	}
+	s.client = s3.NewFromConfig(cfg)
+	return nil
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, reader io.Reader, contentType string, skipIfExists bool) (string, error) {
+	objectKey := buildKey(s.Prefix, key)
+
+	if skipIfExists {
+		if exists, err := s.Exists(ctx, key); err == nil && exists {
+			return s.url(objectKey), nil
+		}
+	}
+
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.Bucket),
+		Key:         aws.String(objectKey),
+		Body:        reader,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return "", fmt.Errorf("goupload: s3 put %s: %w", objectKey, err)
+	}
+
+	return s.url(objectKey), nil
+}
+
+func (s *S3Storage) url(objectKey string) string {
+	if s.CDNBaseURL != "" {
+		return strings.TrimRight(s.CDNBaseURL, "/") + "/" + objectKey
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.Bucket, s.Region, objectKey)
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(buildKey(s.Prefix, key)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(buildKey(s.Prefix, key)),
+	})
+	return err
+}
+
+func (s *S3Storage) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(buildKey(s.Prefix, key)),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
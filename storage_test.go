@@ -0,0 +1,49 @@
+package goupload
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFilesystemStoragePutOverwritesByDefault(t *testing.T) {
+	dir := t.TempDir()
+	storage := &FilesystemStorage{BasePath: dir}
+
+	if _, err := storage.Put(context.Background(), "photo.jpg", strings.NewReader("first"), "image/jpeg", false); err != nil {
+		t.Fatalf("Put (first): %v", err)
+	}
+	if _, err := storage.Put(context.Background(), "photo.jpg", strings.NewReader("second"), "image/jpeg", false); err != nil {
+		t.Fatalf("Put (second): %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "photo.jpg"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != "second" {
+		t.Fatalf("second Put with skipIfExists=false must overwrite the first upload's content, got %q, want %q", got, "second")
+	}
+}
+
+func TestFilesystemStoragePutSkipsWhenSkipIfExistsTrue(t *testing.T) {
+	dir := t.TempDir()
+	storage := &FilesystemStorage{BasePath: dir}
+
+	if _, err := storage.Put(context.Background(), "content-hash.jpg", strings.NewReader("first"), "image/jpeg", true); err != nil {
+		t.Fatalf("Put (first): %v", err)
+	}
+	if _, err := storage.Put(context.Background(), "content-hash.jpg", strings.NewReader("second"), "image/jpeg", true); err != nil {
+		t.Fatalf("Put (second): %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "content-hash.jpg"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != "first" {
+		t.Fatalf("skipIfExists=true should leave the existing (content-addressed) object alone, got %q, want %q", got, "first")
+	}
+}
@@ -0,0 +1,247 @@
+package goupload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/chai2010/webp"
+	"golang.org/x/image/draw"
+)
+
+// VariantSpec describes one derived image to generate alongside the
+// original upload, e.g. a thumbnail or a web-optimized copy.
+type VariantSpec struct {
+	Name    string // used as both the output subdirectory and map key in UploadResult.Variants
+	Width   int
+	Height  int
+	Fit     string // "cover", "contain", or "thumbnail"
+	Format  string // "jpeg", "webp", "png", or "original" to keep the source format
+	Quality int    // 1-100, only used for jpeg/webp
+}
+
+// defaultVariantConcurrency bounds how many variants are resized at once
+// across all uploads, so a burst of large images can't exhaust memory.
+const defaultVariantConcurrency = 4
+
+var variantSemaphore = make(chan struct{}, defaultVariantConcurrency)
+
+// generateVariants decodes the file behind open and writes one resized copy
+// per spec to storage, alongside originalKey. It returns a name -> URL map
+// and skips entirely (without error) for SVGs and animated GIFs, which
+// aren't safe to decode/resize with image.Decode.
+func generateVariants(ctx context.Context, storage Storage, filename string, open func() (io.ReadCloser, error), originalKey string, specs []VariantSpec) (map[string]string, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == ".svg" {
+		return nil, nil
+	}
+
+	src, err := open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	raw, err := io.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if isAnimatedGIF(raw) {
+		return nil, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		// Not a format we can decode/resize (or not a real image); skip
+		// the pipeline rather than failing the whole upload.
+		return nil, nil
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		results  = make(map[string]string, len(specs))
+		firstErr error
+	)
+
+	for _, spec := range specs {
+		spec := spec
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			variantSemaphore <- struct{}{}
+			defer func() { <-variantSemaphore }()
+
+			url, err := renderVariant(ctx, storage, img, ext, originalKey, spec)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("variant %s: %w", spec.Name, err)
+				}
+				return
+			}
+			results[spec.Name] = url
+		}()
+	}
+
+	wg.Wait()
+
+	return results, firstErr
+}
+
+func renderVariant(ctx context.Context, storage Storage, img image.Image, origExt, originalKey string, spec VariantSpec) (string, error) {
+	resized := resizeImage(img, spec.Width, spec.Height, spec.Fit)
+
+	variantExt := origExt
+	switch strings.ToLower(spec.Format) {
+	case "jpeg", "jpg":
+		variantExt = ".jpg"
+	case "png":
+		variantExt = ".png"
+	case "webp":
+		variantExt = ".webp"
+	case "original", "":
+		// keep origExt
+	}
+
+	var buf bytes.Buffer
+	if err := encodeImage(&buf, resized, variantExt, spec.Quality); err != nil {
+		return "", err
+	}
+
+	variantKey := filepath.ToSlash(filepath.Join(filepath.Dir(originalKey), spec.Name, withExt(filepath.Base(originalKey), variantExt)))
+
+	return storage.Put(ctx, variantKey, &buf, contentTypeForExt(variantExt), false)
+}
+
+func resizeImage(src image.Image, width, height int, fit string) image.Image {
+	bounds := src.Bounds()
+	if width <= 0 {
+		width = bounds.Dx()
+	}
+	if height <= 0 {
+		height = bounds.Dy()
+	}
+
+	switch strings.ToLower(fit) {
+	case "contain":
+		width, height = containDimensions(bounds.Dx(), bounds.Dy(), width, height)
+		dst := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+		return dst
+	case "cover", "thumbnail", "":
+		return coverCrop(src, bounds, width, height)
+	default:
+		dst := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+		return dst
+	}
+}
+
+// coverCrop scales src up just enough to fully cover a width x height box
+// without distorting its aspect ratio, then crops the centered overflow —
+// the standard "object-fit: cover" behavior used for "cover"/"thumbnail"
+// variants. A plain draw.CatmullRom.Scale straight into the target box (as
+// "contain" does) would instead stretch non-square sources.
+func coverCrop(src image.Image, bounds image.Rectangle, width, height int) image.Image {
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if srcW == 0 || srcH == 0 {
+		dst := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+		return dst
+	}
+
+	scale := float64(width) / float64(srcW)
+	if s := float64(height) / float64(srcH); s > scale {
+		scale = s
+	}
+	scaledW := int(float64(srcW)*scale + 0.5)
+	scaledH := int(float64(srcH)*scale + 0.5)
+	if scaledW < width {
+		scaledW = width
+	}
+	if scaledH < height {
+		scaledH = height
+	}
+
+	scaled := image.NewRGBA(image.Rect(0, 0, scaledW, scaledH))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), src, bounds, draw.Over, nil)
+
+	offsetX := (scaledW - width) / 2
+	offsetY := (scaledH - height) / 2
+	cropped := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(cropped, cropped.Bounds(), scaled, image.Pt(offsetX, offsetY), draw.Src)
+	return cropped
+}
+
+func containDimensions(srcW, srcH, maxW, maxH int) (int, int) {
+	if srcW == 0 || srcH == 0 {
+		return maxW, maxH
+	}
+	srcRatio := float64(srcW) / float64(srcH)
+	boxRatio := float64(maxW) / float64(maxH)
+	if srcRatio > boxRatio {
+		return maxW, int(float64(maxW) / srcRatio)
+	}
+	return int(float64(maxH) * srcRatio), maxH
+}
+
+func encodeImage(w *bytes.Buffer, img image.Image, ext string, quality int) error {
+	switch strings.ToLower(ext) {
+	case ".png":
+		return png.Encode(w, img)
+	case ".webp":
+		return webp.Encode(w, img, &webp.Options{Quality: float32(qualityOrDefault(quality))})
+	case ".gif":
+		return gif.Encode(w, img, nil)
+	default:
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: qualityOrDefault(quality)})
+	}
+}
+
+func qualityOrDefault(quality int) int {
+	if quality <= 0 || quality > 100 {
+		return 85
+	}
+	return quality
+}
+
+func withExt(filename, ext string) string {
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + ext
+}
+
+func contentTypeForExt(ext string) string {
+	switch strings.ToLower(ext) {
+	case ".png":
+		return "image/png"
+	case ".webp":
+		return "image/webp"
+	case ".gif":
+		return "image/gif"
+	default:
+		return "image/jpeg"
+	}
+}
+
+func isAnimatedGIF(raw []byte) bool {
+	g, err := gif.DecodeAll(bytes.NewReader(raw))
+	if err != nil {
+		return false
+	}
+	return len(g.Image) > 1
+}
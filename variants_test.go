@@ -0,0 +1,68 @@
+package goupload
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// solidImage returns a w x h image filled entirely with c, so a resized
+// copy can be checked for distortion by sampling pixels rather than
+// needing a real decoded photo.
+func solidImage(w, h int, c color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestResizeImageCoverProducesExactTargetSize(t *testing.T) {
+	src := solidImage(400, 100, color.White)
+
+	dst := resizeImage(src, 100, 100, "cover")
+
+	bounds := dst.Bounds()
+	if bounds.Dx() != 100 || bounds.Dy() != 100 {
+		t.Fatalf("resizeImage cover = %dx%d, want 100x100", bounds.Dx(), bounds.Dy())
+	}
+}
+
+func TestResizeImageCoverCropsInsteadOfStretching(t *testing.T) {
+	// A 400x100 source with a red 100x100 marker centered at x=[150,250),
+	// white everywhere else. Covering into a 100x100 box is height-
+	// constrained (scale=1), so a correct center crop takes exactly that
+	// marker region and the whole output should come out solid red. A
+	// naive stretch-to-box resize would instead squash the marker to ~25px
+	// wide, leaving most of the output white.
+	src := image.NewRGBA(image.Rect(0, 0, 400, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 400; x++ {
+			if x >= 150 && x < 250 {
+				src.Set(x, y, color.RGBA{255, 0, 0, 255})
+			} else {
+				src.Set(x, y, color.RGBA{255, 255, 255, 255})
+			}
+		}
+	}
+
+	dst := resizeImage(src, 100, 100, "cover")
+
+	r, g, b, _ := dst.At(5, 50).RGBA()
+	if g != 0 || b != 0 || r == 0 {
+		t.Fatalf("expected cover crop to keep the marker filling the output (red at edge x=5), got r=%d g=%d b=%d", r, g, b)
+	}
+}
+
+func TestResizeImageContainPreservesAspectRatio(t *testing.T) {
+	src := solidImage(400, 100, color.White)
+
+	dst := resizeImage(src, 100, 100, "contain")
+
+	bounds := dst.Bounds()
+	if bounds.Dx() != 100 || bounds.Dy() != 25 {
+		t.Fatalf("resizeImage contain = %dx%d, want 100x25", bounds.Dx(), bounds.Dy())
+	}
+}